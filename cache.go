@@ -0,0 +1,164 @@
+package gumroad
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ErrCacheMiss is returned by Cache.Get when no data is present for the
+// requested key.
+var ErrCacheMiss = errors.New("license: cache miss")
+
+// Cache describes the storage used by Product to persist the result of a
+// license verification for offline use. It is modeled after
+// golang.org/x/crypto/acme/autocert.Cache.
+type Cache interface {
+	// Get returns the data previously stored under key, or ErrCacheMiss if
+	// there is none.
+	Get(ctx context.Context, key string) ([]byte, error)
+
+	// Put stores data under key, overwriting any previous value.
+	Put(ctx context.Context, key string, data []byte) error
+
+	// Delete removes data stored under key, if any.
+	Delete(ctx context.Context, key string) error
+}
+
+// DirCache implements Cache using a directory on the local filesystem. Each
+// key is stored as its own file under the directory, which is created with
+// 0700 permissions on first write if it does not already exist.
+type DirCache string
+
+// Get reads the cached data for key, returning ErrCacheMiss if it's absent.
+func (d DirCache) Get(ctx context.Context, key string) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(string(d), key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrCacheMiss
+	}
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// Put writes data for key, creating the cache directory if needed. It writes
+// to a temporary file in the same directory and renames it into place, so a
+// concurrent Get (from Start's renewal goroutine racing a foreground Verify,
+// for example) or a crash mid-write never observes a truncated entry.
+func (d DirCache) Put(ctx context.Context, key string, data []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(string(d), 0o700); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(string(d), key+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(string(d), key))
+}
+
+// Delete removes the cached data for key, if any.
+func (d DirCache) Delete(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	err := os.Remove(filepath.Join(string(d), key))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// cacheEntry is what Product stores in Cache for a given license.
+type cacheEntry struct {
+	Response   GumroadResponse `json:"response"`
+	VerifiedAt time.Time       `json:"verified_at"`
+}
+
+// cacheKey derives a filesystem-safe cache key from a product ID and license
+// key so that callers never need to sanitize license keys themselves.
+func cacheKey(productID, key string) string {
+	sum := sha256.Sum256([]byte(productID + "\x00" + key))
+	return hex.EncodeToString(sum[:])
+}
+
+// store saves a response received from the Gumroad API to gp.Cache,
+// best-effort, whether or not it passes checkAndValidate.
+func (gp Product) store(ctx context.Context, key string, gumroad GumroadResponse) {
+	data, err := json.Marshal(cacheEntry{Response: gumroad, VerifiedAt: time.Now()})
+	if err != nil {
+		return
+	}
+	_ = gp.Cache.Put(ctx, cacheKey(gp.ProductID, key), data)
+}
+
+// cached returns the cached GumroadResponse for key, if present and within
+// MaxStale.
+func (gp Product) cached(ctx context.Context, key string) (GumroadResponse, error) {
+	data, err := gp.Cache.Get(ctx, cacheKey(gp.ProductID, key))
+	if err != nil {
+		return GumroadResponse{}, err
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return GumroadResponse{}, err
+	}
+	if time.Since(entry.VerifiedAt) > gp.MaxStale {
+		return GumroadResponse{}, errors.New("license: cached entry is stale")
+	}
+	return entry.Response, nil
+}
+
+// Start begins a background goroutine that re-verifies key against the
+// Gumroad API every RenewalInterval (24 hours by default), refreshing the
+// cache on success. If a re-verification determines that the license is no
+// longer valid because it was refunded or its subscription canceled (the
+// error wraps ErrRevoked), OnRevoke is called with the resulting error; other
+// failures do not call it. Start returns immediately; cancel ctx to stop renewal.
+func (gp Product) Start(ctx context.Context, key string) {
+	interval := gp.RenewalInterval
+	if interval <= 0 {
+		interval = 24 * time.Hour
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := gp.Verify(ctx, key); err != nil && gp.OnRevoke != nil && errors.Is(err, ErrRevoked) {
+					gp.OnRevoke(err)
+				}
+			}
+		}
+	}()
+}
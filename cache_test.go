@@ -0,0 +1,160 @@
+package gumroad
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestDirCache(t *testing.T) {
+	t.Parallel()
+	dir := DirCache(t.TempDir())
+	ctx := context.Background()
+
+	if _, err := dir.Get(ctx, "missing"); err != ErrCacheMiss {
+		t.Fatalf("expected ErrCacheMiss, got %v", err)
+	}
+
+	if err := dir.Put(ctx, "key", []byte("data")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := dir.Get(ctx, "key")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(data) != "data" {
+		t.Fatalf("expected %q, got %q", "data", string(data))
+	}
+
+	if err := dir.Delete(ctx, "key"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := dir.Get(ctx, "key"); err != ErrCacheMiss {
+		t.Fatalf("expected ErrCacheMiss after delete, got %v", err)
+	}
+}
+
+func TestVerifyServesCacheOnNetworkError(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bts, _ := json.Marshal(GumroadResponse{Success: true, Purchase: Purchase{ProductID: "product", LicenseKey: license}})
+		_, _ = w.Write(bts)
+	}))
+	t.Cleanup(server.Close)
+
+	p, err := NewProduct("product")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	p.API = server.URL
+	p.Cache = DirCache(t.TempDir())
+	p.MaxStale = time.Hour
+
+	if err := p.Verify(context.Background(), license); err != nil {
+		t.Fatalf("unexpected error priming cache: %v", err)
+	}
+
+	// closing the server means the next call fails with a network error
+	server.Close()
+
+	if err := p.Verify(context.Background(), license); err != nil {
+		t.Fatalf("expected cached verification to be served, got %v", err)
+	}
+}
+
+func TestVerifyDoesNotServeStaleCache(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bts, _ := json.Marshal(GumroadResponse{Success: true, Purchase: Purchase{ProductID: "product", LicenseKey: license}})
+		_, _ = w.Write(bts)
+	}))
+	t.Cleanup(server.Close)
+
+	p, err := NewProduct("product")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	p.API = server.URL
+	p.Cache = DirCache(t.TempDir())
+	// MaxStale left at zero, so cached entries should never be served
+
+	if err := p.Verify(context.Background(), license); err != nil {
+		t.Fatalf("unexpected error priming cache: %v", err)
+	}
+
+	server.Close()
+
+	if err := p.Verify(context.Background(), license); err == nil {
+		t.Fatal("expected an error, since the cached entry should be considered stale")
+	}
+}
+
+func TestStartCallsOnRevokeOnRefund(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bts, _ := json.Marshal(GumroadResponse{Success: true, Purchase: Purchase{ProductID: "product", LicenseKey: license, Refunded: true}})
+		_, _ = w.Write(bts)
+	}))
+	t.Cleanup(server.Close)
+
+	p, err := NewProduct("product")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	p.API = server.URL
+	p.RenewalInterval = time.Millisecond
+
+	revoked := make(chan error, 1)
+	p.OnRevoke = func(err error) { revoked <- err }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	p.Start(ctx, license)
+
+	select {
+	case err := <-revoked:
+		if !errors.Is(err, ErrRevoked) {
+			t.Fatalf("expected ErrRevoked, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for OnRevoke")
+	}
+}
+
+func TestStartDoesNotCallOnRevokeForTransientError(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(server.Close)
+
+	p, err := NewProduct("product")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	p.API = server.URL
+	p.RetryPolicy = RetryPolicy{}
+	p.RenewalInterval = time.Millisecond
+
+	revoked := make(chan error, 1)
+	p.OnRevoke = func(err error) { revoked <- err }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	p.Start(ctx, license)
+
+	select {
+	case err := <-revoked:
+		t.Fatalf("expected OnRevoke not to be called for a transient error, got %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -3,6 +3,7 @@ package gumroad
 
 import (
 	"context"
+	"crypto/ed25519"
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/json"
@@ -21,10 +22,55 @@ type Product struct {
 	ProductID string
 	Client    *http.Client
 	Validate  func(GumroadResponse) error
+
+	// pins backs PinnedSPKIHashes/SetPinnedSPKIHashes. It's nil for a Product
+	// built as a struct literal rather than via NewProduct, in which case
+	// pinning was never wired into Client's transport and both methods are
+	// no-ops.
+	pins *pinSet
+
+	// Cache, when set, stores each response Verify receives from the Gumroad
+	// API, valid or not, so that it can be checked in place of a later call
+	// that fails, whether with a network error or with a 5xx or 429 response
+	// once retries are exhausted. See DirCache for a filesystem-backed
+	// implementation.
+	Cache Cache
+
+	// MaxStale is how old a cached verification may be and still be served
+	// when the upstream call fails. A zero value means cached entries are
+	// never considered fresh enough to serve.
+	MaxStale time.Duration
+
+	// RenewalInterval is how often Start re-verifies a license in the
+	// background. Defaults to 24 hours.
+	RenewalInterval time.Duration
+
+	// OnRevoke, if set, is called by Start when a background re-verification
+	// determines that a previously valid license is no longer valid (e.g. it
+	// was refunded or its subscription was canceled).
+	OnRevoke func(error)
+
+	// OfflineKey, when set, enables VerifyOffline to check licenses signed
+	// offline by cmd/gumroad-sign, without contacting the Gumroad API.
+	OfflineKey ed25519.PublicKey
+
+	// RetryPolicy controls how Verify retries a 5xx or 429 response.
+	// NewProduct sets this to a reasonable default.
+	RetryPolicy RetryPolicy
 }
 
-// NewProduct returns a new GumroadProduct with reasonable defaults.
-func NewProduct(productID string) (Product, error) {
+// NewProduct returns a new Product with reasonable defaults. Any pins
+// passed restrict the certificates trusted for the Gumroad API to those
+// matching one of the pins; see Product.SetPinnedSPKIHashes and PinFromPEM.
+//
+// Pinning is opt-in: NewProduct ships no default pins for api.gumroad.com.
+// Gumroad doesn't publish a pin rotation schedule or a backup pin, so baking
+// in today's leaf/intermediate SPKI hashes would hard-break every caller's
+// Verify calls on the next unannounced certificate rotation, trading a
+// theoretical MITM risk for a guaranteed outage. Callers who want pinning
+// should pass pins here and be prepared to update them when Gumroad rotates
+// certificates.
+func NewProduct(productID string, pins ...[]byte) (Product, error) {
 	// early return if product permalink is empty
 	if productID == "" {
 		return Product{}, errors.New("license: product ID cannot be empty")
@@ -35,11 +81,15 @@ func NewProduct(productID string) (Product, error) {
 	// only return an error if it was unable to find or parse any system certificates.
 	certPool, _ := x509.SystemCertPool()
 
+	ps := &pinSet{pins: pins}
+	tlsConfig := &tls.Config{RootCAs: certPool}
+	pin(tlsConfig, ps)
+
 	// construct a package-level http.RoundTripper to use instead of http.DefaultTransport
 	transport := &http.Transport{
 		// don't use the runtime system's cert pool, since it may include a certificate
 		// that this package does not want to trust
-		TLSClientConfig: &tls.Config{RootCAs: certPool},
+		TLSClientConfig: tlsConfig,
 
 		// since TLSClientConfig above is not nil, HTTP/2 needs to be explicitly enabled
 		ForceAttemptHTTP2: true,
@@ -59,64 +109,126 @@ func NewProduct(productID string) (Product, error) {
 			Timeout:   time.Minute,
 			Transport: transport,
 		},
+		pins:        ps,
+		RetryPolicy: defaultRetryPolicy,
 	}, nil
 }
 
-const maxRetries = 5
+// PinnedSPKIHashes returns the pin set currently enforced against the
+// Gumroad API's certificate, i.e. the last value passed to NewProduct or
+// SetPinnedSPKIHashes. It returns nil for a Product built as a struct
+// literal rather than via NewProduct.
+func (gp Product) PinnedSPKIHashes() [][]byte {
+	if gp.pins == nil {
+		return nil
+	}
+	return gp.pins.get()
+}
 
-// Verify returns the result of VerifyWithContext with the background context.
-func (gp Product) Verify(ctx context.Context, key string) error {
-	return gp.doVerify(ctx, key, 1)
+// SetPinnedSPKIHashes replaces the pin set enforced against the Gumroad
+// API's certificate (see PinFromPEM). Unlike Product's other fields, pinning
+// is wired into Client's transport once by NewProduct, so this method exists
+// to change it afterward; a plain field assignment would not take effect. An
+// empty pin set disables pinning. It is a no-op on a Product built as a
+// struct literal rather than via NewProduct.
+func (gp Product) SetPinnedSPKIHashes(pins [][]byte) {
+	if gp.pins == nil {
+		return
+	}
+	gp.pins.set(pins)
 }
 
-func (gp Product) doVerify(ctx context.Context, key string, try int) error {
-	// early return if license key is empty
+// Verify checks key against the Gumroad API using ctx, retrying per
+// RetryPolicy and falling back to Cache when the API call fails, and returns
+// an error unless the license is valid.
+func (gp Product) Verify(ctx context.Context, key string) error {
 	if key == "" {
 		return errors.New("license: license key cannot be empty")
 	}
-	req, err := http.NewRequestWithContext(ctx, "POST", gp.API, strings.NewReader(url.Values{
-		"product_id":  {gp.ProductID},
-		"license_key": {key},
-	}.Encode()))
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	resp, err := gp.Client.Do(req)
+
+	gumroad, err := gp.doVerify(ctx, key)
 	if err != nil {
-		return fmt.Errorf("license: failed check license: %w", err)
+		if gp.Cache == nil {
+			return err
+		}
+		cached, cerr := gp.cached(ctx, key)
+		if cerr != nil {
+			return err
+		}
+		return gp.checkAndValidate(cached, key)
 	}
 
-	bts, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("license: failed check license: %w", err)
+	if gp.Cache != nil {
+		gp.store(ctx, key, gumroad)
 	}
-	defer resp.Body.Close()
+	return gp.checkAndValidate(gumroad, key)
+}
+
+// doVerify calls the Gumroad API and returns the raw response, retrying on
+// 5xx and 429 responses per gp.RetryPolicy. It does not apply any of the
+// business-rule checks in checkAndValidate, so that a cached response can be
+// validated the same way.
+func (gp Product) doVerify(ctx context.Context, key string) (GumroadResponse, error) {
+	policy := gp.RetryPolicy
 
-	// something on server side, should probably retry...
-	if resp.StatusCode >= 500 {
-		if try == maxRetries {
-			return fmt.Errorf("license: likely gumroad issue: %s", string(bts))
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, "POST", gp.API, strings.NewReader(url.Values{
+			"product_id":  {gp.ProductID},
+			"license_key": {key},
+		}.Encode()))
+		if err != nil {
+			return GumroadResponse{}, err
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		resp, err := gp.Client.Do(req)
+		if err != nil {
+			return GumroadResponse{}, fmt.Errorf("license: failed check license: %w", err)
 		}
-		time.Sleep(time.Duration(try*500) * time.Millisecond)
-		return gp.doVerify(ctx, key, try+1)
-	}
 
-	var gumroad GumroadResponse
-	if err := json.Unmarshal(bts, &gumroad); err != nil {
-		return fmt.Errorf("license: failed check license: %w", err)
+		bts, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return GumroadResponse{}, fmt.Errorf("license: failed check license: %w", err)
+		}
+
+		// something on server side, or we're being rate-limited; retry.
+		if policy.shouldRetry(resp.StatusCode) {
+			if attempt >= policy.MaxRetries {
+				return GumroadResponse{}, fmt.Errorf("license: likely gumroad issue: %s", string(bts))
+			}
+			if err := sleep(ctx, policy.delay(attempt, resp.Header.Get("Retry-After"))); err != nil {
+				return GumroadResponse{}, err
+			}
+			continue
+		}
+
+		var gumroad GumroadResponse
+		if err := json.Unmarshal(bts, &gumroad); err != nil {
+			return GumroadResponse{}, fmt.Errorf("license: failed check license: %w", err)
+		}
+		return gumroad, nil
 	}
+}
+
+// ErrRevoked is wrapped by the error returned by checkAndValidate when a previously
+// valid license has been refunded or its subscription canceled, as opposed
+// to a transient network or API failure. Start uses errors.Is(err,
+// ErrRevoked) to decide when to call OnRevoke.
+var ErrRevoked = errors.New("revoked")
 
+// checkAndValidate applies the business-rule checks to a GumroadResponse,
+// whether it came from a live API call or a cache hit, and runs Validate.
+func (gp Product) checkAndValidate(gumroad GumroadResponse, key string) error {
 	if !gumroad.Success {
 		return fmt.Errorf("license: invalid license: %s", gumroad.Message)
 	}
 
 	if gumroad.Purchase.Refunded {
-		return fmt.Errorf("license: license was refunded and is now invalid")
+		return fmt.Errorf("license: license was refunded and is now invalid: %w", ErrRevoked)
 	}
 
 	if !gumroad.Purchase.SubscriptionCancelledAt.IsZero() {
-		return fmt.Errorf("license: subscription was canceled, license is now invalid")
+		return fmt.Errorf("license: subscription was canceled, license is now invalid: %w", ErrRevoked)
 	}
 
 	if !gumroad.Purchase.SubscriptionFailedAt.IsZero() {
@@ -272,7 +272,7 @@ var testCases = map[string]struct {
 				Refunded: true,
 			},
 		},
-		eeer: "license: license was refunded and is now invalid",
+		eeer: "license: license was refunded and is now invalid: revoked",
 	},
 	"canceled": {
 		product: "product", key: "key",
@@ -282,7 +282,7 @@ var testCases = map[string]struct {
 				SubscriptionCancelledAt: time.Now(),
 			},
 		},
-		eeer: "license: subscription was canceled, license is now invalid",
+		eeer: "license: subscription was canceled, license is now invalid: revoked",
 	},
 	"failed": {
 		product: "product", key: "key",
@@ -0,0 +1,74 @@
+package gumroad
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// offlinePayload is the JSON blob signed into an offline license token.
+type offlinePayload struct {
+	ProductID  string    `json:"product_id"`
+	LicenseKey string    `json:"license_key"`
+	Email      string    `json:"email"`
+	IssuedAt   time.Time `json:"issued_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Nonce      string    `json:"nonce,omitempty"`
+	Refunded   bool      `json:"refunded,omitempty"`
+}
+
+// VerifyOffline verifies an offline license token without contacting the
+// Gumroad API. token is a base64url string of payload||signature, where
+// payload is a JSON-encoded offlinePayload and signature is its trailing
+// 64-byte Ed25519 signature, as minted by cmd/gumroad-sign. OfflineKey must
+// be set to the public key used to sign tokens.
+func (gp Product) VerifyOffline(token string) error {
+	if gp.OfflineKey == nil {
+		return errors.New("license: offline public key not configured")
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return fmt.Errorf("license: invalid offline license token: %w", err)
+	}
+	if len(raw) <= ed25519.SignatureSize {
+		return errors.New("license: invalid offline license token")
+	}
+
+	payload, sig := raw[:len(raw)-ed25519.SignatureSize], raw[len(raw)-ed25519.SignatureSize:]
+	if !ed25519.Verify(gp.OfflineKey, payload, sig) {
+		return errors.New("license: invalid offline license signature")
+	}
+
+	var data offlinePayload
+	if err := json.Unmarshal(payload, &data); err != nil {
+		return fmt.Errorf("license: invalid offline license token: %w", err)
+	}
+
+	if data.ProductID != gp.ProductID {
+		return errors.New("license: invalid product ID")
+	}
+
+	if data.Refunded {
+		return errors.New("license: license was refunded and is now invalid")
+	}
+
+	if !data.ExpiresAt.IsZero() && time.Now().After(data.ExpiresAt) {
+		return errors.New("license: offline license has expired")
+	}
+
+	if gp.Validate != nil {
+		return gp.Validate(GumroadResponse{
+			Success: true,
+			Purchase: Purchase{
+				ProductID:  data.ProductID,
+				LicenseKey: data.LicenseKey,
+				Email:      data.Email,
+			},
+		})
+	}
+	return nil
+}
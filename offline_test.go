@@ -0,0 +1,78 @@
+package gumroad
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func mintOfflineToken(t *testing.T, priv ed25519.PrivateKey, p offlinePayload) string {
+	t.Helper()
+	data, err := json.Marshal(p)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sig := ed25519.Sign(priv, data)
+	return base64.RawURLEncoding.EncodeToString(append(data, sig...))
+}
+
+func TestVerifyOffline(t *testing.T) {
+	t.Parallel()
+
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	p := Product{ProductID: "product", OfflineKey: pub}
+	now := time.Now()
+
+	token := mintOfflineToken(t, priv, offlinePayload{
+		ProductID:  "product",
+		LicenseKey: license,
+		Email:      "foo@example.com",
+		IssuedAt:   now,
+		ExpiresAt:  now.Add(24 * time.Hour),
+	})
+	if err := p.VerifyOffline(token); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expired := mintOfflineToken(t, priv, offlinePayload{
+		ProductID: "product",
+		ExpiresAt: now.Add(-time.Hour),
+	})
+	if err := p.VerifyOffline(expired); err == nil || !strings.Contains(err.Error(), "expired") {
+		t.Fatalf("expected an expiry error, got %v", err)
+	}
+
+	refunded := mintOfflineToken(t, priv, offlinePayload{
+		ProductID: "product",
+		Refunded:  true,
+	})
+	if err := p.VerifyOffline(refunded); err == nil || !strings.Contains(err.Error(), "refunded") {
+		t.Fatalf("expected a refund error, got %v", err)
+	}
+
+	wrongProduct := mintOfflineToken(t, priv, offlinePayload{ProductID: "other"})
+	if err := p.VerifyOffline(wrongProduct); err == nil || !strings.Contains(err.Error(), "invalid product ID") {
+		t.Fatalf("expected a product ID mismatch error, got %v", err)
+	}
+
+	_, otherPriv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tampered := mintOfflineToken(t, otherPriv, offlinePayload{ProductID: "product"})
+	if err := p.VerifyOffline(tampered); err == nil || !strings.Contains(err.Error(), "invalid offline license signature") {
+		t.Fatalf("expected a signature error, got %v", err)
+	}
+
+	noKey := Product{ProductID: "product"}
+	if err := noKey.VerifyOffline(token); err == nil {
+		t.Fatal("expected an error when OfflineKey is not configured")
+	}
+}
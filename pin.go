@@ -0,0 +1,85 @@
+package gumroad
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// pinSet holds the SPKI pins enforced by a Product's VerifyPeerCertificate
+// callback behind a mutex. It's allocated once by NewProduct and shared, via
+// pointer, by every copy of the Product value it's attached to, so that
+// Product.SetPinnedSPKIHashes takes effect on the next handshake even though
+// Product itself is passed around by value.
+type pinSet struct {
+	mu   sync.RWMutex
+	pins [][]byte
+}
+
+func (ps *pinSet) get() [][]byte {
+	ps.mu.RLock()
+	defer ps.mu.RUnlock()
+	return ps.pins
+}
+
+func (ps *pinSet) set(pins [][]byte) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	ps.pins = pins
+}
+
+// PinFromPEM decodes a PEM-encoded certificate and returns the SHA-256 hash
+// of its SubjectPublicKeyInfo, suitable for use in Product's pin set.
+func PinFromPEM(pemBytes []byte) ([]byte, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, errors.New("license: no certificate found in PEM data")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("license: failed to parse certificate: %w", err)
+	}
+	return spkiHash(cert), nil
+}
+
+// spkiHash computes the SHA-256 hash of a certificate's SubjectPublicKeyInfo.
+func spkiHash(cert *x509.Certificate) []byte {
+	sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return sum[:]
+}
+
+// verifyPins returns a tls.Config.VerifyPeerCertificate callback that reads
+// ps fresh on every handshake and fails it unless at least one certificate
+// in a verified chain has a SubjectPublicKeyInfo hash present in the current
+// pin set. An empty pin set leaves the connection unrestricted.
+func verifyPins(ps *pinSet) func(_ [][]byte, verifiedChains [][]*x509.Certificate) error {
+	return func(_ [][]byte, verifiedChains [][]*x509.Certificate) error {
+		pins := ps.get()
+		if len(pins) == 0 {
+			return nil
+		}
+		for _, chain := range verifiedChains {
+			for _, cert := range chain {
+				hash := spkiHash(cert)
+				for _, pin := range pins {
+					if bytes.Equal(hash, pin) {
+						return nil
+					}
+				}
+			}
+		}
+		return errors.New("license: no certificate in the chain matches a pinned public key")
+	}
+}
+
+// pin wires a live VerifyPeerCertificate callback into tlsConfig, backed by
+// ps, so that later calls to Product.SetPinnedSPKIHashes are enforced on
+// subsequent handshakes.
+func pin(tlsConfig *tls.Config, ps *pinSet) {
+	tlsConfig.VerifyPeerCertificate = verifyPins(ps)
+}
@@ -0,0 +1,99 @@
+package gumroad
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestPinFromPEMInvalid(t *testing.T) {
+	t.Parallel()
+	if _, err := PinFromPEM([]byte("not a pem")); err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func newPinnedClient(t *testing.T, server *httptest.Server, pins [][]byte) Product {
+	t.Helper()
+	p, err := NewProduct("product", pins...)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	p.API = server.URL
+
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+	transport := p.Client.Transport.(*http.Transport)
+	transport.TLSClientConfig.RootCAs = pool
+
+	return p
+}
+
+func TestPinnedSPKIHashesRejectsUnpinnedCert(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bts, _ := json.Marshal(GumroadResponse{Success: true, Purchase: Purchase{ProductID: "product"}})
+		_, _ = w.Write(bts)
+	}))
+	t.Cleanup(server.Close)
+
+	// a pin that cannot possibly match the test server's certificate
+	p := newPinnedClient(t, server, [][]byte{[]byte("not-a-real-pin-0123456789012345")})
+
+	err := p.Verify(context.Background(), license)
+	if err == nil || !strings.Contains(err.Error(), "failed check license") {
+		t.Fatalf("expected pin mismatch to fail the handshake, got %v", err)
+	}
+}
+
+func TestPinnedSPKIHashesAcceptsPinnedCert(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bts, _ := json.Marshal(GumroadResponse{Success: true, Purchase: Purchase{ProductID: "product", LicenseKey: license}})
+		_, _ = w.Write(bts)
+	}))
+	t.Cleanup(server.Close)
+
+	hash := spkiHash(server.Certificate())
+	p := newPinnedClient(t, server, [][]byte{hash})
+
+	if err := p.Verify(context.Background(), license); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestSetPinnedSPKIHashesTakesEffectImmediately(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bts, _ := json.Marshal(GumroadResponse{Success: true, Purchase: Purchase{ProductID: "product", LicenseKey: license}})
+		_, _ = w.Write(bts)
+	}))
+	t.Cleanup(server.Close)
+
+	// constructed with a pin that cannot possibly match the test server
+	p := newPinnedClient(t, server, [][]byte{[]byte("not-a-real-pin-0123456789012345")})
+
+	if err := p.Verify(context.Background(), license); err == nil {
+		t.Fatal("expected the initial, unrelated pin to reject the handshake")
+	}
+
+	// a plain field assignment would be a no-op; SetPinnedSPKIHashes must be
+	// used so later handshakes see the update
+	p.SetPinnedSPKIHashes([][]byte{spkiHash(server.Certificate())})
+
+	if err := p.Verify(context.Background(), license); err != nil {
+		t.Fatalf("expected updated pin set to accept the handshake, got %v", err)
+	}
+
+	if got := p.PinnedSPKIHashes(); len(got) != 1 || !bytes.Equal(got[0], spkiHash(server.Certificate())) {
+		t.Fatalf("expected PinnedSPKIHashes to reflect the update, got %v", got)
+	}
+}
@@ -0,0 +1,126 @@
+package gumroad
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how Product retries a verification request when the
+// Gumroad API responds with a 5xx or 429 status code.
+type RetryPolicy struct {
+	// MaxRetries is how many additional attempts are made after the first
+	// request fails. A zero value disables retries; NewProduct sets a
+	// default of 5.
+	MaxRetries int
+
+	// BaseDelay is the delay before the first retry. Each subsequent retry
+	// doubles it, up to MaxDelay.
+	BaseDelay time.Duration
+
+	// MaxDelay caps the delay between retries. Zero means uncapped.
+	MaxDelay time.Duration
+
+	// Jitter, when true, randomizes each delay between zero and the
+	// computed delay ("full jitter"), to avoid a thundering herd of clients
+	// retrying in lockstep.
+	Jitter bool
+}
+
+// defaultRetryPolicy is used by NewProduct.
+var defaultRetryPolicy = RetryPolicy{
+	MaxRetries: 5,
+	BaseDelay:  500 * time.Millisecond,
+	MaxDelay:   8 * time.Second,
+	Jitter:     true,
+}
+
+// shouldRetry reports whether statusCode warrants a retry under this policy.
+func (rp RetryPolicy) shouldRetry(statusCode int) bool {
+	return statusCode >= 500 || statusCode == http.StatusTooManyRequests
+}
+
+// delay computes the backoff delay for the given (zero-based) attempt,
+// honoring a Retry-After header when present. MaxDelay caps the result
+// either way.
+func (rp RetryPolicy) delay(attempt int, retryAfter string) time.Duration {
+	if d, ok := parseRetryAfter(retryAfter); ok {
+		if rp.MaxDelay > 0 && d > rp.MaxDelay {
+			d = rp.MaxDelay
+		}
+		return d
+	}
+
+	d := rp.BaseDelay
+	if d > 0 {
+		// Double d up to attempt times, stopping early once MaxDelay is
+		// reached or exceeded. The iteration cap bounds the work done even
+		// for a very large attempt, and doubling a positive duration must
+		// strictly increase it, so next <= d reliably detects overflow.
+		maxIters := attempt
+		if maxIters > 40 {
+			maxIters = 40
+		}
+		overflowed := false
+		for i := 0; i < maxIters; i++ {
+			next := d * 2
+			if next <= d {
+				overflowed = true
+				break
+			}
+			d = next
+			if rp.MaxDelay > 0 && d >= rp.MaxDelay {
+				break
+			}
+		}
+		if overflowed {
+			if rp.MaxDelay > 0 {
+				d = rp.MaxDelay
+			} else {
+				d = math.MaxInt64
+			}
+		}
+	}
+	if rp.MaxDelay > 0 && d > rp.MaxDelay {
+		d = rp.MaxDelay
+	}
+	if rp.Jitter && d > 0 {
+		d = time.Duration(rand.Int63n(int64(d)))
+	}
+	return d
+}
+
+// parseRetryAfter parses a Retry-After header value, either as a number of
+// seconds or an HTTP date, per RFC 7231 §7.1.3.
+func parseRetryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// sleep waits for d, returning ctx.Err() immediately if ctx is canceled
+// first instead of blocking for the full duration.
+func sleep(ctx context.Context, d time.Duration) error {
+	select {
+	case <-time.After(d):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,159 @@
+package gumroad
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRetries429(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		bts, _ := json.Marshal(GumroadResponse{Success: true, Purchase: Purchase{ProductID: "product", LicenseKey: license}})
+		_, _ = w.Write(bts)
+	}))
+	t.Cleanup(server.Close)
+
+	p, err := NewProduct("product")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	p.API = server.URL
+	p.RetryPolicy = RetryPolicy{MaxRetries: 5, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond}
+
+	if err := p.Verify(context.Background(), license); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 3 {
+		t.Fatalf("expected 3 calls, got %d", calls)
+	}
+}
+
+func TestRetryAfterHeader(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	var gotDelay time.Duration
+	start := time.Now()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		gotDelay = time.Since(start)
+		bts, _ := json.Marshal(GumroadResponse{Success: true, Purchase: Purchase{ProductID: "product", LicenseKey: license}})
+		_, _ = w.Write(bts)
+	}))
+	t.Cleanup(server.Close)
+
+	p, err := NewProduct("product")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	p.API = server.URL
+	p.RetryPolicy = RetryPolicy{MaxRetries: 1, BaseDelay: time.Millisecond}
+
+	if err := p.Verify(context.Background(), license); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotDelay < time.Second {
+		t.Fatalf("expected to honor Retry-After: 1, only waited %s", gotDelay)
+	}
+}
+
+func TestRetryContextCancellationDuringBackoff(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(server.Close)
+
+	p, err := NewProduct("product")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	p.API = server.URL
+	p.RetryPolicy = RetryPolicy{MaxRetries: 5, BaseDelay: time.Hour}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err = p.Verify(ctx, license)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("expected cancellation to abort backoff quickly, took %s", elapsed)
+	}
+}
+
+func TestRetryAfterIsCappedByMaxDelay(t *testing.T) {
+	t.Parallel()
+
+	policy := RetryPolicy{MaxRetries: 1, MaxDelay: time.Millisecond}
+	d := policy.delay(0, "3600")
+	if d != time.Millisecond {
+		t.Fatalf("expected Retry-After to be capped at MaxDelay, got %s", d)
+	}
+}
+
+func TestRetryDelayDoesNotOverflow(t *testing.T) {
+	t.Parallel()
+
+	policy := RetryPolicy{BaseDelay: 500 * time.Millisecond, MaxDelay: 30 * time.Second}
+	if d := policy.delay(62, ""); d != 30*time.Second {
+		t.Fatalf("expected delay to be capped at MaxDelay despite overflow, got %s", d)
+	}
+
+	// with no MaxDelay set, doubling must still saturate instead of
+	// wrapping to a small or negative duration
+	unbounded := RetryPolicy{BaseDelay: 500 * time.Millisecond}
+	if d := unbounded.delay(62, ""); d <= 0 {
+		t.Fatalf("expected a large positive delay, got %s", d)
+	}
+}
+
+func TestRetryZeroBaseDelayStaysZero(t *testing.T) {
+	t.Parallel()
+
+	policy := RetryPolicy{BaseDelay: 0}
+	if d := policy.delay(5, ""); d != 0 {
+		t.Fatalf("expected a zero BaseDelay to produce no backoff, got %s", d)
+	}
+}
+
+func TestRetryZeroPolicyDisablesRetries(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(server.Close)
+
+	// a Product built as a struct literal, without NewProduct's default
+	// RetryPolicy, should fail on the first 5xx rather than retry.
+	p := Product{API: server.URL, ProductID: "product", Client: server.Client()}
+	if err := p.Verify(context.Background(), license); err == nil {
+		t.Fatal("expected an error")
+	}
+	if calls != 1 {
+		t.Fatalf("expected exactly 1 call with a zero-value RetryPolicy, got %d", calls)
+	}
+}